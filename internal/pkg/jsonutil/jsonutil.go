@@ -0,0 +1,132 @@
+// Package jsonutil provides json helpers for policy documents
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// maxNumberExponent bounds the exponents normalizeNumber rewrites; larger ones
+// are kept as written.
+const maxNumberExponent = 1 << 30
+
+// CanonicalizePolicyJSON returns the policy with sorted keys, no insignificant
+// whitespace and numbers in a single spelling, so 1.0 and 1e3 become 1 and 1000.
+// A single-element array that is the value of an object key, such as a lone
+// Action or Principal qcs entry, is collapsed to its element unless that element
+// is itself an array. Arrays nested in arrays are never collapsed, so
+// [["a","b"]] stays distinct from ["a","b"]. The result is a fixed point:
+// canonicalizing it again returns it unchanged.
+func CanonicalizePolicyJSON(policy string) (string, error) {
+	dec := json.NewDecoder(strings.NewReader(policy))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("invalid policy json: %s", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return "", fmt.Errorf("invalid policy json: unexpected data after top-level value")
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(canonicalize(v)); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// PolicyDiffSuppressFunc suppresses diffs between semantically equal policies
+func PolicyDiffSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	o, err := CanonicalizePolicyJSON(old)
+	if err != nil {
+		return false
+	}
+	n, err := CanonicalizePolicyJSON(new)
+	if err != nil {
+		return false
+	}
+	return o == n
+}
+
+func canonicalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			e = canonicalize(e)
+			if a, ok := e.([]interface{}); ok && len(a) == 1 {
+				if _, nested := a[0].([]interface{}); !nested {
+					e = a[0]
+				}
+			}
+			t[k] = e
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = canonicalize(e)
+		}
+		return t
+	case json.Number:
+		return normalizeNumber(t)
+	}
+	return v
+}
+
+// normalizeNumber rewrites a json number to one spelling for its exact value.
+func normalizeNumber(n json.Number) json.Number {
+	s := string(n)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil || e > maxNumberExponent || e < -maxNumberExponent {
+			return n
+		}
+		exp, s = e, s[:i]
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		exp -= len(s) - i - 1
+		s = s[:i] + s[i+1:]
+	}
+
+	digits := strings.TrimLeft(s, "0")
+	if digits == "" {
+		return "0"
+	}
+	trimmed := strings.TrimRight(digits, "0")
+	exp += len(digits) - len(trimmed)
+	digits = trimmed
+
+	// point is the position of the decimal point relative to the digits.
+	point := len(digits) + exp
+	var out string
+	switch {
+	case exp >= 0 && point <= 21:
+		out = digits + strings.Repeat("0", exp)
+	case point > 0 && point <= 21:
+		out = digits[:point] + "." + digits[point:]
+	case point <= 0 && point > -6:
+		out = "0." + strings.Repeat("0", -point) + digits
+	default:
+		out = digits[:1]
+		if len(digits) > 1 {
+			out += "." + digits[1:]
+		}
+		out += "e" + strconv.Itoa(point-1)
+	}
+	if neg {
+		out = "-" + out
+	}
+	return json.Number(out)
+}
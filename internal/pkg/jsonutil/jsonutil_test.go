@@ -0,0 +1,211 @@
+package jsonutil
+
+import "testing"
+
+func TestCanonicalizePolicyJSON(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy string
+		want   string
+	}{
+		{
+			name:   "sorted keys",
+			policy: `{"version":"2.0","statement":{"effect":"allow"}}`,
+			want:   `{"statement":{"effect":"allow"},"version":"2.0"}`,
+		},
+		{
+			name:   "insignificant whitespace",
+			policy: "{\n  \"b\" : 1,\n\t\"a\" : \"x\"\n}\n",
+			want:   `{"a":"x","b":1}`,
+		},
+		{
+			name:   "nested statement array",
+			policy: `{"Statement":[{"Effect":"Allow","Action":["name/cos:GetObject"]}]}`,
+			want:   `{"Statement":{"Action":"name/cos:GetObject","Effect":"Allow"}}`,
+		},
+		{
+			name:   "principal array",
+			policy: `{"Principal":{"qcs":["qcs::cam::uin/1:uin/2"]}}`,
+			want:   `{"Principal":{"qcs":"qcs::cam::uin/1:uin/2"}}`,
+		},
+		{
+			name:   "multi-element array keeps order",
+			policy: `{"Action":["b","a"]}`,
+			want:   `{"Action":["b","a"]}`,
+		},
+		{
+			name:   "number spellings normalized",
+			policy: `{"a":1.0,"b":1e3,"c":-0.50,"d":1.5E-7,"e":-0.00,"f":0.000120}`,
+			want:   `{"a":1,"b":1000,"c":-0.5,"d":1.5e-7,"e":0,"f":0.00012}`,
+		},
+		{
+			name:   "large number normalized exactly",
+			policy: `{"a":12345678901234567890123,"b":1E+21,"c":100}`,
+			want:   `{"a":1.2345678901234567890123e22,"b":1e21,"c":100}`,
+		},
+		{
+			name:   "out of range exponent kept",
+			policy: `{"a":1e99999999999}`,
+			want:   `{"a":1e99999999999}`,
+		},
+		{
+			name:   "html characters not escaped",
+			policy: `{"a":"<&>"}`,
+			want:   `{"a":"<&>"}`,
+		},
+		{
+			name:   "array of array kept",
+			policy: `[["a","b"]]`,
+			want:   `[["a","b"]]`,
+		},
+		{
+			name:   "array nested in array kept",
+			policy: `[["x"]]`,
+			want:   `[["x"]]`,
+		},
+		{
+			name:   "object nested in arrays kept",
+			policy: `[[{"a":1}]]`,
+			want:   `[[{"a":1}]]`,
+		},
+		{
+			name:   "top-level single-element array kept",
+			policy: `["x"]`,
+			want:   `["x"]`,
+		},
+		{
+			name:   "key value holding nested array kept",
+			policy: `{"Action":[["x"]]}`,
+			want:   `{"Action":[["x"]]}`,
+		},
+		{
+			name:   "statement and action collapsed",
+			policy: `{"Statement":[{"Action":["x"],"Resource":["a","b"]}]}`,
+			want:   `{"Statement":{"Action":"x","Resource":["a","b"]}}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := CanonicalizePolicyJSON(c.policy)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+			again, err := CanonicalizePolicyJSON(got)
+			if err != nil {
+				t.Fatalf("unexpected error on canonical output: %s", err)
+			}
+			if again != got {
+				t.Errorf("not idempotent: %s canonicalized again to %s", got, again)
+			}
+		})
+	}
+}
+
+func TestCanonicalizePolicyJSONError(t *testing.T) {
+	cases := []struct {
+		name   string
+		policy string
+	}{
+		{name: "empty", policy: ""},
+		{name: "trailing value", policy: `{"a":1} {"b":2}`},
+		{name: "trailing brace", policy: `{"a":1}}`},
+		{name: "malformed", policy: `{"a":}`},
+		{name: "unterminated", policy: `{"a":1`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got, err := CanonicalizePolicyJSON(c.policy); err == nil {
+				t.Errorf("expected error, got %s", got)
+			}
+		})
+	}
+}
+
+func TestPolicyDiffSuppressFunc(t *testing.T) {
+	cases := []struct {
+		name string
+		old  string
+		new  string
+		want bool
+	}{
+		{
+			name: "reordered keys and whitespace",
+			old:  `{"a":"x","b":1}`,
+			new:  ` { "b" : 1, "a" : "x" } `,
+			want: true,
+		},
+		{
+			name: "statement array vs object",
+			old:  `{"Statement":[{"Effect":"Allow","Principal":{"qcs":["x"]}}]}`,
+			new:  `{"Statement":{"Principal":{"qcs":"x"},"Effect":"Allow"}}`,
+			want: true,
+		},
+		{
+			name: "principal array vs scalar",
+			old:  `{"Principal":{"qcs":["x"]}}`,
+			new:  `{"Principal":{"qcs":"x"}}`,
+			want: true,
+		},
+		{
+			name: "reordered multi-element array",
+			old:  `{"Action":["a","b"]}`,
+			new:  `{"Action":["b","a"]}`,
+			want: false,
+		},
+		{
+			name: "equal numbers spelled differently",
+			old:  `{"Condition":{"numeric_equal":{"a":1.0,"b":1e3}}}`,
+			new:  `{"Condition":{"numeric_equal":{"a":1,"b":1000}}}`,
+			want: true,
+		},
+		{
+			name: "different numbers",
+			old:  `{"a":1.5}`,
+			new:  `{"a":1.50001}`,
+			want: false,
+		},
+		{
+			name: "nested single-element arrays",
+			old:  `{"Action":[["x"]]}`,
+			new:  `{"Action":"x"}`,
+			want: false,
+		},
+		{
+			name: "nested array vs flat array",
+			old:  `[["a","b"]]`,
+			new:  `["a","b"]`,
+			want: false,
+		},
+		{
+			name: "create with empty old",
+			old:  "",
+			new:  `{"a":1}`,
+			want: false,
+		},
+		{
+			name: "invalid new",
+			old:  `{"a":1}`,
+			new:  `{"a":1`,
+			want: false,
+		},
+		{
+			name: "both invalid",
+			old:  `{`,
+			new:  `{`,
+			want: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := PolicyDiffSuppressFunc("policy", c.old, c.new, nil); got != c.want {
+				t.Errorf("got %t, want %t", got, c.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,10 @@
 // Package xac_paas provides paas service
 package xac_paas
 
-import "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/jchalex/terraform-provider-xac/internal/pkg/jsonutil"
+)
 
 // ResourceXaCPaaSCOS resource xac_paas_cos
 func ResourceXaCPaaSCOS() *schema.Resource {
@@ -37,9 +40,10 @@ func ResourceXaCPaaSCOS() *schema.Resource {
 				Description: "The access control list.",
 			},
 			"policy": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The concrete policy for bucket or object.",
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: jsonutil.PolicyDiffSuppressFunc,
+				Description:      "The concrete policy for bucket or object.",
 			},
 		},
 	}